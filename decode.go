@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"math"
-	"os"
 	"reflect"
 	"strconv"
 	"strings"
@@ -12,12 +11,46 @@ import (
 )
 
 const (
-	mapEnvTagName string = "mpe"
+	mapEnvTagName      string = "mpe"
+	mapEnvDefaultTag   string = "mpe-default"
+	mapEnvRequiredTag  string = "mpe-required"
+	mapEnvSeparatorTag string = "mpe-separator"
+	mapEnvPrefixTag    string = "mpe-prefix"
+	mapEnvLayoutTag    string = "mpe-layout"
 )
 
+var timeType = reflect.TypeOf(time.Time{})
+var locationType = reflect.TypeOf(time.Location{})
+var setterType = reflect.TypeOf((*Setter)(nil)).Elem()
+
+// Setter is implemented by types that want to own their own decoding from a raw environment variable string, such as
+// net.IP, url.URL, or a domain-specific wrapper. When a field's type implements Setter, it is used in place of the
+// reflect-based decoding in decodeValue.
+type Setter interface {
+	SetValue(s string) error
+}
+
+// Options configures how Decode and (*Loader).Decode handle per-field failures.
+type Options struct {
+	// StopOnFirstError reverts to returning as soon as the first field fails to decode, instead of the default of
+	// collecting every failure into a DecodeErrors.
+	StopOnFirstError bool
+}
+
 // Decode decode current environmental variables into an output structure.
 // Output must be a pointer to a struct.
 func Decode(v interface{}) error {
+	return decode(v, osEnvProvider{}, Options{})
+}
+
+// DecodeWithOptions behaves like Decode but accepts Options controlling how per-field errors are reported.
+func DecodeWithOptions(v interface{}, opts Options) error {
+	return decode(v, osEnvProvider{}, opts)
+}
+
+// decode is the shared implementation behind Decode and (*Loader).Decode, looking up values through p instead of
+// assuming the OS environment.
+func decode(v interface{}, p Provider, opts Options) error {
 	val := reflect.ValueOf(v)
 	t := reflect.TypeOf(v)
 
@@ -41,43 +74,154 @@ func Decode(v interface{}) error {
 
 	newVal := reflect.New(t)
 
-	for i := 0; i < newVal.Elem().NumField(); i++ {
+	if err := decodeStruct(newVal, "", p, opts); err != nil {
+		return err
+	}
+
+	val.Set(newVal.Elem())
+
+	return nil
+}
+
+// decodeStruct walks the fields of the struct pointed to by v, decoding each one from the value p reports for its
+// environment variable, with prefix prepended to every lookup. It is the shared implementation behind Decode and
+// the mpe-prefix tag, which recurses into nested structs with an accumulated prefix instead of falling through to
+// json.Unmarshal. Unless opts.StopOnFirstError is set, every field is attempted and all failures are returned
+// together as a DecodeErrors.
+func decodeStruct(v reflect.Value, prefix string, p Provider, opts Options) error {
+	t := v.Type().Elem()
+
+	var errs DecodeErrors
+
+	for i := 0; i < v.Elem().NumField(); i++ {
 		fTyp := t.Field(i)
 		isUnexported := fTyp.PkgPath != ""
 		if isUnexported {
 			continue
 		}
 
+		fVal := v.Elem().Field(i)
+
+		if nestedPrefix, hasPrefix := fTyp.Tag.Lookup(mapEnvPrefixTag); hasPrefix && isNestedStruct(fTyp.Type) {
+			fAddr := fVal.Addr()
+			if fVal.Kind() == reflect.Ptr {
+				if fVal.IsNil() {
+					fVal.Set(reflect.New(fVal.Type().Elem()))
+				}
+				fAddr = fVal
+			}
+
+			if err := decodeStruct(fAddr, prefix+nestedPrefix, p, opts); err != nil {
+				if opts.StopOnFirstError {
+					return err
+				}
+
+				if nested, ok := err.(DecodeErrors); ok {
+					errs = append(errs, nested...)
+				} else {
+					errs = append(errs, err)
+				}
+			}
+			continue
+		}
+
 		var s string
 		var tag string
 		var ok bool
 
 		fieldTags := getFieldTags(fTyp)
 		for _, tag = range fieldTags {
-			if s, ok = os.LookupEnv(tag); ok {
+			if s, ok = p.LookupEnv(prefix + tag); ok {
 				break
 			}
 		}
+
+		if !ok {
+			if def, hasDefault := fTyp.Tag.Lookup(mapEnvDefaultTag); hasDefault {
+				s, ok = def, true
+			}
+		}
+
+		if !ok {
+			if req, _ := strconv.ParseBool(fTyp.Tag.Get(mapEnvRequiredTag)); req {
+				err := newDecodeError(fmt.Sprintf("required field '%s' is missing", prefix+tag), prefix+tag, nil)
+				if opts.StopOnFirstError {
+					return err
+				}
+				errs = append(errs, err)
+			}
+			continue
+		}
+
 		if len(s) == 0 {
 			continue
 		}
 
-		fVal := newVal.Elem().Field(i)
-		err := decodeValue(s, fVal.Addr())
-		if err != nil {
-			return newDecodeError(fmt.Sprintf("unable to decode value in field '%s'", tag), tag, err)
+		dOpts := decodeOpts{
+			separator: fTyp.Tag.Get(mapEnvSeparatorTag),
+			layout:    fTyp.Tag.Get(mapEnvLayoutTag),
+		}
+
+		if err := decodeValue(s, fVal.Addr(), dOpts); err != nil {
+			decErr := newDecodeError(fmt.Sprintf("unable to decode value in field '%s'", prefix+tag), prefix+tag, err)
+			if opts.StopOnFirstError {
+				return decErr
+			}
+			errs = append(errs, decErr)
 		}
 	}
 
-	val.Set(newVal.Elem())
+	if len(errs) > 0 {
+		return errs
+	}
 
 	return nil
 }
 
-// decodeValue decodes a string variable as a value. Base types are parsed using `strconv`. Maps, structs, arrays and
-// slices are decoded as json objects using standard json unmarshaling. Channels and functions are skipped, as they're
-// not supported.
-func decodeValue(s string, v reflect.Value) error {
+// isNestedStruct reports whether t (or the struct it points to) is eligible for mpe-prefix recursion, i.e. it is a
+// struct other than one of the types decodeValue already special-cases.
+func isNestedStruct(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return t.Kind() == reflect.Struct && t != timeType && t != locationType && !reflect.PointerTo(t).Implements(setterType)
+}
+
+// decodeOpts carries the per-field decoding options parsed from struct tags.
+type decodeOpts struct {
+	// separator, from mpe-separator, delimits slice/map entries instead of requiring JSON.
+	separator string
+	// layout, from mpe-layout, overrides the default RFC3339Nano layout used to parse time.Time fields.
+	layout string
+}
+
+// decodeValue decodes a string variable as a value. Types implementing Setter decode themselves. time.Duration and
+// time.Location are special-cased, base types are parsed using `strconv`, and maps, structs, arrays and slices are
+// decoded as json objects using standard json unmarshaling, unless opts.separator is set, in which case maps and
+// slices are decoded from a delimited string instead.
+func decodeValue(s string, v reflect.Value, opts decodeOpts) error {
+	if setter, ok := v.Interface().(Setter); ok {
+		return setter.SetValue(s)
+	}
+
+	switch i := v.Interface().(type) {
+	case *time.Duration:
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		*i = d
+		return nil
+	case *time.Location:
+		loc, err := time.LoadLocation(s)
+		if err != nil {
+			return err
+		}
+		*i = *loc
+		return nil
+	}
+
 	switch v.Elem().Kind() {
 	case reflect.String:
 		v.Elem().SetString(s)
@@ -112,25 +256,32 @@ func decodeValue(s string, v reflect.Value) error {
 		}
 		v.Elem().SetComplex(f)
 	case reflect.Map, reflect.Struct, reflect.Array, reflect.Slice:
-		i := v.Interface()
-		switch i.(type) {
+		raw := v.Interface()
+		switch raw.(type) {
 		case *time.Time:
-			t, err := parseTime(s)
+			layout := opts.layout
+			if len(layout) == 0 {
+				layout = time.RFC3339Nano
+			}
+			t, err := parseTime(s, layout)
 			if err != nil {
 				return err
 			}
 			v.Elem().Set(reflect.ValueOf(t))
 		default:
-			err := json.Unmarshal([]byte(s), i)
+			if len(opts.separator) > 0 {
+				return decodeWithSeparator(s, v, opts.separator)
+			}
+			err := json.Unmarshal([]byte(s), raw)
 			if err != nil {
 				return err
 			}
 		}
 	case reflect.Ptr:
-		if v.IsNil() {
-			v.Set(reflect.New(v.Type().Elem()))
+		if v.Elem().IsNil() {
+			v.Elem().Set(reflect.New(v.Type().Elem().Elem()))
 		}
-		return decodeValue(s, v.Elem())
+		return decodeValue(s, v.Elem(), opts)
 	case reflect.Chan, reflect.Func:
 	default:
 		return fmt.Errorf("unsupported field kind: %s", v.Elem().Kind().String())
@@ -138,11 +289,55 @@ func decodeValue(s string, v reflect.Value) error {
 	return nil
 }
 
-// parseTime parses a string as time.Time. It supports the RFC3339 format, unix seconds, and json marshalled time.Time
-// structs.
-func parseTime(s string) (time.Time, error) {
-	// attempt to parse time as RFC3339 string
-	t, err := time.Parse(time.RFC3339Nano, s)
+// decodeWithSeparator decodes a slice or map field from a string delimited by sep, as an alternative to the default
+// JSON decoding used by decodeValue. Map entries are split again on "=" into key/value pairs.
+func decodeWithSeparator(s string, v reflect.Value, sep string) error {
+	parts := strings.Split(s, sep)
+
+	switch v.Elem().Kind() {
+	case reflect.Slice:
+		sl := reflect.MakeSlice(v.Elem().Type(), len(parts), len(parts))
+		for idx, part := range parts {
+			if err := decodeValue(part, sl.Index(idx).Addr(), decodeOpts{}); err != nil {
+				return err
+			}
+		}
+		v.Elem().Set(sl)
+	case reflect.Map:
+		keyTyp := v.Elem().Type().Key()
+		valTyp := v.Elem().Type().Elem()
+		mp := reflect.MakeMapWithSize(v.Elem().Type(), len(parts))
+		for _, part := range parts {
+			kv := strings.SplitN(part, "=", 2)
+			if len(kv) != 2 {
+				return fmt.Errorf("invalid map entry %q, expected key=value", part)
+			}
+
+			key := reflect.New(keyTyp)
+			if err := decodeValue(kv[0], key, decodeOpts{}); err != nil {
+				return err
+			}
+
+			val := reflect.New(valTyp)
+			if err := decodeValue(kv[1], val, decodeOpts{}); err != nil {
+				return err
+			}
+
+			mp.SetMapIndex(key.Elem(), val.Elem())
+		}
+		v.Elem().Set(mp)
+	default:
+		return fmt.Errorf("%s does not support mpe-separator", v.Elem().Kind().String())
+	}
+
+	return nil
+}
+
+// parseTime parses a string as time.Time using layout. It supports unix seconds and json marshalled time.Time
+// structs as fallbacks, regardless of layout.
+func parseTime(s string, layout string) (time.Time, error) {
+	// attempt to parse time using the configured layout
+	t, err := time.Parse(layout, s)
 	if err == nil {
 		return t, nil
 	}
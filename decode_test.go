@@ -0,0 +1,205 @@
+package mapenv
+
+import (
+	"testing"
+	"time"
+)
+
+type stringSetter struct {
+	value string
+}
+
+func (s *stringSetter) SetValue(v string) error {
+	s.value = v
+	return nil
+}
+
+func (s stringSetter) String() string {
+	return s.value
+}
+
+func TestDecodeDefaultAndRequired(t *testing.T) {
+	type Config struct {
+		Host string `mpe:"TEST_DECODE_HOST" mpe-default:"localhost"`
+		Port int    `mpe:"TEST_DECODE_PORT" mpe-required:"true"`
+	}
+
+	t.Setenv("TEST_DECODE_PORT", "8080")
+
+	var cfg Config
+	if err := Decode(&cfg); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	if cfg.Host != "localhost" {
+		t.Errorf("Host = %q, want %q", cfg.Host, "localhost")
+	}
+	if cfg.Port != 8080 {
+		t.Errorf("Port = %d, want %d", cfg.Port, 8080)
+	}
+}
+
+func TestDecodeRequiredMissing(t *testing.T) {
+	type Config struct {
+		Port int `mpe:"TEST_DECODE_MISSING_PORT" mpe-required:"true"`
+	}
+
+	var cfg Config
+	if err := Decode(&cfg); err == nil {
+		t.Fatal("Decode() error = nil, want error for missing required field")
+	}
+}
+
+func TestDecodePrefix(t *testing.T) {
+	type Database struct {
+		Host string `mpe:"HOST"`
+		Port int    `mpe:"PORT"`
+	}
+	type Config struct {
+		DB Database `mpe-prefix:"TEST_DECODE_DB_"`
+	}
+
+	t.Setenv("TEST_DECODE_DB_HOST", "db.internal")
+	t.Setenv("TEST_DECODE_DB_PORT", "5432")
+
+	var cfg Config
+	if err := Decode(&cfg); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	if cfg.DB.Host != "db.internal" || cfg.DB.Port != 5432 {
+		t.Errorf("DB = %+v, want {db.internal 5432}", cfg.DB)
+	}
+}
+
+func TestDecodeSeparator(t *testing.T) {
+	type Config struct {
+		Tags []string `mpe:"TEST_DECODE_TAGS" mpe-separator:","`
+	}
+
+	t.Setenv("TEST_DECODE_TAGS", "a,b,c")
+
+	var cfg Config
+	if err := Decode(&cfg); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	want := []string{"a", "b", "c"}
+	if len(cfg.Tags) != len(want) {
+		t.Fatalf("Tags = %v, want %v", cfg.Tags, want)
+	}
+	for i := range want {
+		if cfg.Tags[i] != want[i] {
+			t.Errorf("Tags[%d] = %q, want %q", i, cfg.Tags[i], want[i])
+		}
+	}
+}
+
+func TestDecodeSetter(t *testing.T) {
+	type Config struct {
+		Custom stringSetter `mpe:"TEST_DECODE_CUSTOM"`
+	}
+
+	t.Setenv("TEST_DECODE_CUSTOM", "hello")
+
+	var cfg Config
+	if err := Decode(&cfg); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if cfg.Custom.value != "hello" {
+		t.Errorf("Custom.value = %q, want %q", cfg.Custom.value, "hello")
+	}
+}
+
+func TestDecodeDuration(t *testing.T) {
+	type Config struct {
+		Timeout time.Duration `mpe:"TEST_DECODE_TIMEOUT"`
+	}
+
+	t.Setenv("TEST_DECODE_TIMEOUT", "90m")
+
+	var cfg Config
+	if err := Decode(&cfg); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if cfg.Timeout != 90*time.Minute {
+		t.Errorf("Timeout = %v, want %v", cfg.Timeout, 90*time.Minute)
+	}
+}
+
+func TestDecodeLocation(t *testing.T) {
+	type Config struct {
+		Loc *time.Location `mpe:"TEST_DECODE_LOC"`
+	}
+
+	t.Setenv("TEST_DECODE_LOC", "America/New_York")
+
+	var cfg Config
+	if err := Decode(&cfg); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if cfg.Loc.String() != "America/New_York" {
+		t.Errorf("Loc = %v, want %v", cfg.Loc, "America/New_York")
+	}
+}
+
+func TestDecodeLayout(t *testing.T) {
+	type Config struct {
+		Day time.Time `mpe:"TEST_DECODE_DAY" mpe-layout:"2006-01-02"`
+	}
+
+	t.Setenv("TEST_DECODE_DAY", "2024-03-01")
+
+	var cfg Config
+	if err := Decode(&cfg); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	want := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	if !cfg.Day.Equal(want) {
+		t.Errorf("Day = %v, want %v", cfg.Day, want)
+	}
+}
+
+func TestDecodeAggregatesAllFieldErrors(t *testing.T) {
+	type Config struct {
+		A int `mpe:"TEST_DECODE_AGG_A"`
+		B int `mpe:"TEST_DECODE_AGG_B"`
+	}
+
+	t.Setenv("TEST_DECODE_AGG_A", "not-an-int")
+	t.Setenv("TEST_DECODE_AGG_B", "also-not-an-int")
+
+	var cfg Config
+	err := Decode(&cfg)
+	if err == nil {
+		t.Fatal("Decode() error = nil, want aggregated errors")
+	}
+
+	errs, ok := err.(DecodeErrors)
+	if !ok {
+		t.Fatalf("Decode() error type = %T, want DecodeErrors", err)
+	}
+	if len(errs) != 2 {
+		t.Fatalf("len(errs) = %d, want 2", len(errs))
+	}
+}
+
+func TestDecodeWithOptionsStopOnFirstError(t *testing.T) {
+	type Config struct {
+		A int `mpe:"TEST_DECODE_STOP_A"`
+		B int `mpe:"TEST_DECODE_STOP_B"`
+	}
+
+	t.Setenv("TEST_DECODE_STOP_A", "not-an-int")
+	t.Setenv("TEST_DECODE_STOP_B", "also-not-an-int")
+
+	var cfg Config
+	err := DecodeWithOptions(&cfg, Options{StopOnFirstError: true})
+	if err == nil {
+		t.Fatal("DecodeWithOptions() error = nil, want error")
+	}
+	if _, ok := err.(DecodeError); !ok {
+		t.Fatalf("DecodeWithOptions() error type = %T, want DecodeError", err)
+	}
+}
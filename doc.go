@@ -0,0 +1,107 @@
+package mapenv
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+const mapEnvDescriptionTag string = "mpe-description"
+
+// FieldDoc describes a single field that Decode reads from the environment: the name(s) it's read from, its Go
+// type, whether it's required, its default value, and a human-readable description from mpe-description.
+type FieldDoc struct {
+	Name        string
+	Type        string
+	Required    bool
+	Default     string
+	HasDefault  bool
+	Description string
+}
+
+// Describe walks v the same way Decode does and returns a FieldDoc for every field it would read from the
+// environment, in struct declaration order. v must be a struct or a pointer to one. Nested structs tagged with
+// mpe-prefix are expanded in place, with the prefix prepended to their fields' names, matching Decode's behavior.
+func Describe(v interface{}) ([]FieldDoc, error) {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, newDecodeError(fmt.Sprintf("cannot describe value of type: %T", v), "", nil)
+	}
+
+	return describeStruct(t, "")
+}
+
+func describeStruct(t reflect.Type, prefix string) ([]FieldDoc, error) {
+	var docs []FieldDoc
+
+	for i := 0; i < t.NumField(); i++ {
+		fTyp := t.Field(i)
+		if fTyp.PkgPath != "" {
+			continue
+		}
+
+		if nestedPrefix, hasPrefix := fTyp.Tag.Lookup(mapEnvPrefixTag); hasPrefix && isNestedStruct(fTyp.Type) {
+			nestedTyp := fTyp.Type
+			for nestedTyp.Kind() == reflect.Ptr {
+				nestedTyp = nestedTyp.Elem()
+			}
+
+			nested, err := describeStruct(nestedTyp, prefix+nestedPrefix)
+			if err != nil {
+				return nil, err
+			}
+
+			docs = append(docs, nested...)
+			continue
+		}
+
+		names := getFieldTags(fTyp)
+		for i, name := range names {
+			names[i] = prefix + name
+		}
+
+		def, hasDefault := fTyp.Tag.Lookup(mapEnvDefaultTag)
+		required, _ := strconv.ParseBool(fTyp.Tag.Get(mapEnvRequiredTag))
+
+		docs = append(docs, FieldDoc{
+			Name:        strings.Join(names, ","),
+			Type:        fTyp.Type.String(),
+			Required:    required,
+			Default:     def,
+			HasDefault:  hasDefault,
+			Description: fTyp.Tag.Get(mapEnvDescriptionTag),
+		})
+	}
+
+	return docs, nil
+}
+
+// Usage writes a human-readable table of every environment variable v declares, as reported by Describe, to w. It
+// is meant to back a service's `--help`-style output.
+func Usage(v interface{}, w io.Writer) error {
+	docs, err := Describe(v)
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "NAME\tTYPE\tREQUIRED\tDEFAULT\tDESCRIPTION")
+	for _, d := range docs {
+		def := d.Default
+		if !d.HasDefault {
+			def = "-"
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%t\t%s\t%s\n", d.Name, d.Type, d.Required, def, d.Description)
+	}
+
+	return tw.Flush()
+}
@@ -0,0 +1,66 @@
+package mapenv
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDescribe(t *testing.T) {
+	type Database struct {
+		Host string `mpe:"HOST" mpe-default:"localhost" mpe-description:"database hostname"`
+		Port int    `mpe:"PORT" mpe-required:"true"`
+	}
+	type Config struct {
+		Name string   `mpe:"TEST_DOC_NAME"`
+		DB   Database `mpe-prefix:"TEST_DOC_DB_"`
+	}
+
+	docs, err := Describe(&Config{})
+	if err != nil {
+		t.Fatalf("Describe() error = %v", err)
+	}
+	if len(docs) != 3 {
+		t.Fatalf("len(docs) = %d, want 3", len(docs))
+	}
+
+	if docs[0].Name != "TEST_DOC_NAME" {
+		t.Errorf("docs[0].Name = %q, want %q", docs[0].Name, "TEST_DOC_NAME")
+	}
+
+	host := docs[1]
+	if host.Name != "TEST_DOC_DB_HOST" {
+		t.Errorf("docs[1].Name = %q, want %q", host.Name, "TEST_DOC_DB_HOST")
+	}
+	if !host.HasDefault || host.Default != "localhost" {
+		t.Errorf("docs[1] default = %q, %v, want %q, true", host.Default, host.HasDefault, "localhost")
+	}
+	if host.Description != "database hostname" {
+		t.Errorf("docs[1].Description = %q, want %q", host.Description, "database hostname")
+	}
+
+	port := docs[2]
+	if port.Name != "TEST_DOC_DB_PORT" {
+		t.Errorf("docs[2].Name = %q, want %q", port.Name, "TEST_DOC_DB_PORT")
+	}
+	if !port.Required {
+		t.Error("docs[2].Required = false, want true")
+	}
+}
+
+func TestUsage(t *testing.T) {
+	type Config struct {
+		Host string `mpe:"TEST_DOC_HOST" mpe-default:"localhost" mpe-description:"the host to bind"`
+	}
+
+	var buf strings.Builder
+	if err := Usage(&Config{}, &buf); err != nil {
+		t.Fatalf("Usage() error = %v", err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{"TEST_DOC_HOST", "localhost", "the host to bind"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Usage() output missing %q:\n%s", want, out)
+		}
+	}
+}
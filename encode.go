@@ -0,0 +1,194 @@
+package mapenv
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Encode walks v the same way Decode does and returns the env-var name/value pairs it would produce, the inverse of
+// Decode: scalars are formatted with `strconv`, time.Time uses RFC3339Nano, time.Duration and time.Location use
+// their String method, and maps, structs, arrays and slices are otherwise JSON-marshaled, matching what
+// decodeValue accepts. v must be a struct or a pointer to one. Fields tagged `mpe:",omitempty"` are skipped when
+// they hold their zero value.
+func Encode(v interface{}) (map[string]string, error) {
+	t := reflect.TypeOf(v)
+	val := reflect.ValueOf(v)
+
+	if t == nil {
+		return nil, newDecodeError("cannot encode nil value", "", nil)
+	}
+
+	for t.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return nil, newDecodeError(fmt.Sprintf("cannot encode nil value of type: %s", t.String()), "", nil)
+		}
+		t = t.Elem()
+		val = val.Elem()
+	}
+
+	if t.Kind() != reflect.Struct {
+		return nil, newDecodeError(fmt.Sprintf("cannot encode value of type: %s", t.String()), "", nil)
+	}
+
+	if !val.CanAddr() {
+		addressable := reflect.New(t).Elem()
+		addressable.Set(val)
+		val = addressable
+	}
+
+	out := make(map[string]string)
+	if err := encodeStruct(val, "", out); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// EncodeToWriter writes v to w in .env file format, one `KEY=value` pair per line sorted by key, quoting values
+// that contain whitespace (including newlines), `=`, `#` or a quote character. The output can be read back by NewDotEnvProvider.
+func EncodeToWriter(v interface{}, w io.Writer) error {
+	values, err := Encode(v)
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if _, err := fmt.Fprintf(w, "%s=%s\n", k, quoteDotEnvValue(values[k])); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func encodeStruct(v reflect.Value, prefix string, out map[string]string) error {
+	t := v.Type()
+
+	for i := 0; i < v.NumField(); i++ {
+		fTyp := t.Field(i)
+		if fTyp.PkgPath != "" {
+			continue
+		}
+
+		fVal := v.Field(i)
+
+		if nestedPrefix, hasPrefix := fTyp.Tag.Lookup(mapEnvPrefixTag); hasPrefix && isNestedStruct(fTyp.Type) {
+			nestedVal := fVal
+			if nestedVal.Kind() == reflect.Ptr {
+				if nestedVal.IsNil() {
+					continue
+				}
+				nestedVal = nestedVal.Elem()
+			}
+
+			if err := encodeStruct(nestedVal, prefix+nestedPrefix, out); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if hasOmitEmptyTag(fTyp) && fVal.IsZero() {
+			continue
+		}
+
+		name := prefix + getFieldTags(fTyp)[0]
+
+		s, err := encodeValue(fVal)
+		if err != nil {
+			return newDecodeError(fmt.Sprintf("unable to encode value in field '%s'", name), name, err)
+		}
+
+		out[name] = s
+	}
+
+	return nil
+}
+
+// hasOmitEmptyTag reports whether t's mpe tag carries an omitempty option, analogous to encoding/json.
+func hasOmitEmptyTag(t reflect.StructField) bool {
+	parts := strings.Split(t.Tag.Get(mapEnvTagName), ",")
+	for _, p := range parts[1:] {
+		if p == "omitempty" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// encodeValue is the inverse of decodeValue: it renders v as the string an env var would need to hold for Decode
+// to reproduce it. Types implementing Setter are expected to round-trip through fmt.Stringer rather than through
+// JSON, since decodeValue feeds them a plain string via SetValue.
+func encodeValue(v reflect.Value) (string, error) {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return "", nil
+		}
+		v = v.Elem()
+	}
+
+	if v.CanAddr() {
+		if _, ok := v.Addr().Interface().(Setter); ok {
+			s, ok := v.Addr().Interface().(fmt.Stringer)
+			if !ok {
+				return "", fmt.Errorf("type %s implements Setter but not fmt.Stringer, cannot encode", v.Type().String())
+			}
+			return s.String(), nil
+		}
+	}
+
+	switch i := v.Interface().(type) {
+	case time.Time:
+		return i.Format(time.RFC3339Nano), nil
+	case time.Duration:
+		return i.String(), nil
+	case time.Location:
+		return i.String(), nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64), nil
+	case reflect.Complex64, reflect.Complex128:
+		return strconv.FormatComplex(v.Complex(), 'g', -1, 128), nil
+	case reflect.Map, reflect.Struct, reflect.Array, reflect.Slice:
+		b, err := json.Marshal(v.Interface())
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	default:
+		return "", fmt.Errorf("unsupported field kind: %s", v.Kind().String())
+	}
+}
+
+// quoteDotEnvValue double-quotes s, escaping embedded quotes, if it contains a character that would otherwise
+// break .env parsing: whitespace (including newlines), '=', '#', or a single or double quote.
+func quoteDotEnvValue(s string) string {
+	if !strings.ContainsAny(s, "=#\"'") && strings.IndexFunc(s, unicode.IsSpace) < 0 {
+		return s
+	}
+
+	return strconv.Quote(s)
+}
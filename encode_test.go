@@ -0,0 +1,50 @@
+package mapenv
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEncodeDecodeRoundTripSpecialTypes(t *testing.T) {
+	type Config struct {
+		At     time.Time      `mpe:"AT"`
+		For    time.Duration  `mpe:"FOR"`
+		Loc    *time.Location `mpe:"LOC"`
+		Custom stringSetter   `mpe:"CUSTOM"`
+	}
+
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("time.LoadLocation() error = %v", err)
+	}
+
+	in := Config{
+		At:     time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+		For:    90 * time.Minute,
+		Loc:    loc,
+		Custom: stringSetter{value: "hello"},
+	}
+
+	values, err := Encode(&in)
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	var out Config
+	if err := NewLoader(MapProvider(values)).Decode(&out); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	if !in.At.Equal(out.At) {
+		t.Errorf("At = %v, want %v", out.At, in.At)
+	}
+	if out.For != in.For {
+		t.Errorf("For = %v, want %v", out.For, in.For)
+	}
+	if out.Loc.String() != in.Loc.String() {
+		t.Errorf("Loc = %v, want %v", out.Loc, in.Loc)
+	}
+	if out.Custom.value != in.Custom.value {
+		t.Errorf("Custom = %v, want %v", out.Custom, in.Custom)
+	}
+}
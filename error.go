@@ -1,6 +1,9 @@
 package mapenv
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 type DecodeError struct {
 	description string
@@ -30,3 +33,20 @@ func (d DecodeError) Error() string {
 	}
 	return fmt.Sprintf("%s: err %v", d.description, d.err)
 }
+
+// DecodeErrors aggregates every per-field error found during a single Decode call, so a misconfigured service
+// reports all of its bad fields at once instead of failing one at a time. Each element is normally a DecodeError.
+type DecodeErrors []error
+
+func (e DecodeErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap allows errors.Is and errors.As to match against any of the aggregated errors individually.
+func (e DecodeErrors) Unwrap() []error {
+	return e
+}
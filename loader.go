@@ -0,0 +1,36 @@
+package mapenv
+
+// Loader decodes environment-shaped configuration by composing one or more Providers. Providers are consulted in
+// the order they were given; the first one reporting a value wins, so callers can layer e.g. a ".env.local" file
+// over a ".env" file over the process environment.
+type Loader struct {
+	providers []Provider
+}
+
+// NewLoader builds a Loader that looks up values across providers in order.
+func NewLoader(providers ...Provider) *Loader {
+	return &Loader{providers: providers}
+}
+
+// LookupEnv implements Provider by walking l's providers in order, so a Loader can itself be nested inside another
+// Loader's provider list.
+func (l *Loader) LookupEnv(name string) (string, bool) {
+	for _, p := range l.providers {
+		if s, ok := p.LookupEnv(name); ok {
+			return s, ok
+		}
+	}
+
+	return "", false
+}
+
+// Decode decodes v the same way the package-level Decode does, except values are looked up through l's providers
+// instead of assuming the OS environment.
+func (l *Loader) Decode(v interface{}) error {
+	return decode(v, l, Options{})
+}
+
+// DecodeWithOptions behaves like Decode but accepts Options controlling how per-field errors are reported.
+func (l *Loader) DecodeWithOptions(v interface{}, opts Options) error {
+	return decode(v, l, opts)
+}
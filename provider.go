@@ -0,0 +1,140 @@
+package mapenv
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Provider looks up a raw string value by name, mirroring the (string, bool) signature of os.LookupEnv. A Loader
+// composes one or more Providers, consulting them in order until one reports a value.
+type Provider interface {
+	LookupEnv(name string) (string, bool)
+}
+
+// osEnvProvider is the Provider backing the package-level Decode: it reads directly from the process environment.
+type osEnvProvider struct{}
+
+func (osEnvProvider) LookupEnv(name string) (string, bool) {
+	return os.LookupEnv(name)
+}
+
+// MapProvider is an in-memory Provider backed by a map, primarily useful for layering fixed values over other
+// providers in tests.
+type MapProvider map[string]string
+
+func (m MapProvider) LookupEnv(name string) (string, bool) {
+	v, ok := m[name]
+	return v, ok
+}
+
+// FlagProvider looks up values from command-line flags named after the same tag used for env var lookups, e.g.
+// `--DB_HOST=localhost` or `--DB_HOST localhost`. Flags given without a value default to "true", so they can back
+// boolean fields.
+type FlagProvider struct {
+	values map[string]string
+}
+
+// NewFlagProvider parses args into a FlagProvider. Pass os.Args[1:] to read the process's own CLI flags.
+//
+// Because the parser has no type information for the flags it's reading, a boolean-style flag immediately followed
+// by a non-"--"-prefixed token consumes that token as its value rather than defaulting to "true": `--verbose
+// input.txt` decodes as VERBOSE=input.txt, not VERBOSE=true with "input.txt" left over as a positional argument.
+// Callers mixing boolean flags with positional arguments should place positional arguments before any flags, or
+// give the flag an explicit value (`--verbose=true`).
+func NewFlagProvider(args []string) *FlagProvider {
+	fp := &FlagProvider{values: make(map[string]string)}
+
+	for i := 0; i < len(args); i++ {
+		if !strings.HasPrefix(args[i], "--") {
+			continue
+		}
+		arg := strings.TrimPrefix(args[i], "--")
+
+		if eq := strings.IndexByte(arg, '='); eq >= 0 {
+			fp.values[arg[:eq]] = arg[eq+1:]
+			continue
+		}
+
+		if i+1 < len(args) && !strings.HasPrefix(args[i+1], "--") {
+			i++
+			fp.values[arg] = args[i]
+			continue
+		}
+
+		fp.values[arg] = "true"
+	}
+
+	return fp
+}
+
+func (fp *FlagProvider) LookupEnv(name string) (string, bool) {
+	v, ok := fp.values[name]
+	return v, ok
+}
+
+// NewDotEnvProvider reads a dotenv-formatted file from path and returns a Provider backed by its contents. Lines
+// are `KEY=VALUE` pairs; a leading `export ` is stripped, blank lines and lines starting with `#` are ignored, and
+// values may be single- or double-quoted to preserve whitespace.
+func NewDotEnvProvider(path string) (Provider, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := parseDotEnv(string(data))
+	if err != nil {
+		return nil, err
+	}
+
+	return MapProvider(values), nil
+}
+
+func parseDotEnv(s string) (map[string]string, error) {
+	values := make(map[string]string)
+
+	for i, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "export "))
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		eq := strings.IndexByte(line, '=')
+		if eq < 0 {
+			return nil, fmt.Errorf("dotenv: line %d: missing '='", i+1)
+		}
+
+		key := strings.TrimSpace(line[:eq])
+		val, err := unquoteDotEnvValue(strings.TrimSpace(line[eq+1:]))
+		if err != nil {
+			return nil, fmt.Errorf("dotenv: line %d: %w", i+1, err)
+		}
+
+		values[key] = val
+	}
+
+	return values, nil
+}
+
+// unquoteDotEnvValue strips the quoting/escaping conventions dotenv files allow around values: double-quoted values
+// support backslash escapes via strconv.Unquote, single-quoted values are taken literally, and unquoted values have
+// any trailing "# ..." comment stripped. A '#' only starts a comment when it's preceded by whitespace, so an
+// unquoted value like "#FF0000" is kept intact rather than being mistaken for a comment.
+func unquoteDotEnvValue(s string) (string, error) {
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		return strconv.Unquote(s)
+	}
+
+	if len(s) >= 2 && s[0] == '\'' && s[len(s)-1] == '\'' {
+		return s[1 : len(s)-1], nil
+	}
+
+	for i := 1; i < len(s); i++ {
+		if s[i] == '#' && (s[i-1] == ' ' || s[i-1] == '\t') {
+			return strings.TrimSpace(s[:i]), nil
+		}
+	}
+
+	return s, nil
+}
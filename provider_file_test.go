@@ -0,0 +1,50 @@
+package mapenv
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewDotEnvProvider(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	content := "export FOO=bar\nBAZ=\"hello world\"\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+
+	p, err := NewDotEnvProvider(path)
+	if err != nil {
+		t.Fatalf("NewDotEnvProvider() error = %v", err)
+	}
+
+	if v, ok := p.LookupEnv("FOO"); !ok || v != "bar" {
+		t.Errorf("LookupEnv(FOO) = %q, %v, want %q, true", v, ok, "bar")
+	}
+	if v, ok := p.LookupEnv("BAZ"); !ok || v != "hello world" {
+		t.Errorf("LookupEnv(BAZ) = %q, %v, want %q, true", v, ok, "hello world")
+	}
+}
+
+func TestNewDotEnvProviderMissingFile(t *testing.T) {
+	if _, err := NewDotEnvProvider(filepath.Join(t.TempDir(), "nope.env")); err == nil {
+		t.Fatal("NewDotEnvProvider() error = nil, want error for missing file")
+	}
+}
+
+func TestLoaderFallbackOrder(t *testing.T) {
+	local := MapProvider{"HOST": "local.internal"}
+	base := MapProvider{"HOST": "base.internal", "PORT": "5432"}
+
+	l := NewLoader(local, base)
+
+	if v, ok := l.LookupEnv("HOST"); !ok || v != "local.internal" {
+		t.Errorf("LookupEnv(HOST) = %q, %v, want %q, true", v, ok, "local.internal")
+	}
+	if v, ok := l.LookupEnv("PORT"); !ok || v != "5432" {
+		t.Errorf("LookupEnv(PORT) = %q, %v, want %q, true", v, ok, "5432")
+	}
+	if _, ok := l.LookupEnv("MISSING"); ok {
+		t.Error("LookupEnv(MISSING) = _, true, want false")
+	}
+}
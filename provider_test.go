@@ -0,0 +1,56 @@
+package mapenv
+
+import "testing"
+
+func TestParseDotEnv(t *testing.T) {
+	input := "# a comment\n\nexport FOO=bar\nBAZ=\"hello world\"\nQUX='raw $value'\nINLINE=val # trailing comment\n"
+
+	values, err := parseDotEnv(input)
+	if err != nil {
+		t.Fatalf("parseDotEnv() error = %v", err)
+	}
+
+	want := map[string]string{
+		"FOO":    "bar",
+		"BAZ":    "hello world",
+		"QUX":    "raw $value",
+		"INLINE": "val",
+	}
+
+	for k, v := range want {
+		if got := values[k]; got != v {
+			t.Errorf("values[%q] = %q, want %q", k, got, v)
+		}
+	}
+}
+
+func TestParseDotEnvMissingEquals(t *testing.T) {
+	if _, err := parseDotEnv("NOVALUE\n"); err == nil {
+		t.Fatal("parseDotEnv() error = nil, want error for line without '='")
+	}
+}
+
+func TestParseDotEnvUnquotedHashNotPrecededByWhitespace(t *testing.T) {
+	values, err := parseDotEnv("COLOR=#FF0000\n")
+	if err != nil {
+		t.Fatalf("parseDotEnv() error = %v", err)
+	}
+
+	if got := values["COLOR"]; got != "#FF0000" {
+		t.Errorf(`values["COLOR"] = %q, want %q`, got, "#FF0000")
+	}
+}
+
+func TestFlagProvider(t *testing.T) {
+	fp := NewFlagProvider([]string{"--HOST=localhost", "--PORT", "8080", "--VERBOSE"})
+
+	if v, ok := fp.LookupEnv("HOST"); !ok || v != "localhost" {
+		t.Errorf("LookupEnv(HOST) = %q, %v, want %q, true", v, ok, "localhost")
+	}
+	if v, ok := fp.LookupEnv("PORT"); !ok || v != "8080" {
+		t.Errorf("LookupEnv(PORT) = %q, %v, want %q, true", v, ok, "8080")
+	}
+	if v, ok := fp.LookupEnv("VERBOSE"); !ok || v != "true" {
+		t.Errorf("LookupEnv(VERBOSE) = %q, %v, want %q, true", v, ok, "true")
+	}
+}